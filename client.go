@@ -22,6 +22,26 @@ type invokeConfig struct {
 	header   map[string]string
 	dialOpts []grpc.DialOption
 	callOpts []grpc.CallOption
+
+	// useServerReflection marks that the method's wire types must be
+	// resolved via the gRPC Server Reflection Protocol instead of being
+	// known upfront from the in/out values.
+	//
+	// See: WithServerReflection().
+	useServerReflection bool
+
+	// retry, when set, wraps the call in an exponential-backoff retry loop.
+	//
+	// See: WithRetry().
+	retry *RetryPolicy
+
+	// useReattach drives dialing the server advertised by the
+	// GRPCMOCK_REATTACH environment variable instead of the method's own
+	// host. reattachName is stored but does not affect dialing.
+	//
+	// See: WithReattach().
+	useReattach  bool
+	reattachName string
 }
 
 // InvokeOption sets invoker config.
@@ -35,12 +55,24 @@ func InvokeUnary(
 	out interface{},
 	opts ...InvokeOption,
 ) error {
-	ctx, conn, method, callOpts, err := prepInvoke(ctx, method, opts...)
+	ctx, conn, method, cfg, callOpts, err := prepInvokeWithConfig(ctx, method, opts...)
 	if err != nil {
 		return err
 	}
 
-	return conn.Invoke(ctx, method, in, out, callOpts...)
+	call := func(ctx context.Context) error {
+		if cfg.useServerReflection {
+			return invokeUnaryViaReflection(ctx, conn, method, in, out, callOpts...)
+		}
+
+		return conn.Invoke(ctx, method, in, out, callOpts...)
+	}
+
+	if cfg.retry != nil {
+		return retryLoop(ctx, cfg.retry, call)
+	}
+
+	return call(ctx)
 }
 
 // InvokeServerStream invokes a server-stream method.
@@ -51,47 +83,69 @@ func InvokeServerStream(
 	handle func(stream grpc.ClientStream) error,
 	opts ...InvokeOption,
 ) error {
-	ctx, conn, method, callOpts, err := prepInvoke(ctx, method, opts...)
+	ctx, conn, method, cfg, callOpts, err := prepInvokeWithConfig(ctx, method, opts...)
 	if err != nil {
 		return err
 	}
 
-	desc := &grpc.StreamDesc{ServerStreams: true}
+	call := func(ctx context.Context) error {
+		if cfg.useServerReflection {
+			return invokeServerStreamViaReflection(ctx, conn, method, in, handle, callOpts...)
+		}
 
-	stream, err := conn.NewStream(ctx, desc, method, callOpts...)
-	if err != nil {
-		return err
-	}
+		desc := &grpc.StreamDesc{ServerStreams: true}
 
-	if err := stream.SendMsg(in); err != nil {
-		return err
-	}
+		stream, err := conn.NewStream(ctx, desc, method, callOpts...)
+		if err != nil {
+			return err
+		}
 
-	if err := stream.CloseSend(); err != nil {
-		return err
+		if err := stream.SendMsg(in); err != nil {
+			return err
+		}
+
+		if err := stream.CloseSend(); err != nil {
+			return err
+		}
+
+		if handle == nil {
+			return nil
+		}
+
+		return handle(stream)
 	}
 
-	if handle == nil {
-		return nil
+	if cfg.retry != nil {
+		return retryLoop(ctx, cfg.retry, call)
 	}
 
-	return handle(stream)
+	return call(ctx)
 }
 
-func prepInvoke(ctx context.Context, method string, opts ...InvokeOption) (context.Context, *grpc.ClientConn, string, []grpc.CallOption, error) {
-	addr, method, err := parseMethod(method)
-	if err != nil {
-		return ctx, nil, "", nil, fmt.Errorf("coulld not parse method url: %w", err)
+func prepInvokeWithConfig(ctx context.Context, method string, opts ...InvokeOption) (context.Context, *grpc.ClientConn, string, *invokeConfig, []grpc.CallOption, error) {
+	ctx, cfg, dialOpts, callOpts := invokeOptions(ctx, opts...)
+
+	var (
+		addr string
+		err  error
+	)
+
+	if cfg.useReattach {
+		addr, method, dialOpts, err = reattachDialTarget(method, dialOpts)
+	} else {
+		addr, method, err = parseMethod(method)
 	}
 
-	ctx, dialOpts, callOpts := invokeOptions(ctx, opts...)
+	if err != nil {
+		return ctx, nil, "", nil, nil, fmt.Errorf("coulld not parse method url: %w", err)
+	}
 
 	conn, err := grpc.DialContext(ctx, addr, dialOpts...)
 	if err != nil {
-		return ctx, nil, "", nil, err
+		return ctx, nil, "", nil, nil, err
 	}
 
-	return ctx, conn, method, callOpts, err
+	return ctx, conn, method, cfg, callOpts, err
 }
 
 func parseMethod(method string) (string, string, error) {
@@ -115,20 +169,20 @@ func parseMethod(method string) (string, string, error) {
 	return addr.String(), method, nil
 }
 
-func invokeOptions(ctx context.Context, opts ...InvokeOption) (context.Context, []grpc.DialOption, []grpc.CallOption) {
-	cfg := invokeConfig{
+func invokeOptions(ctx context.Context, opts ...InvokeOption) (context.Context, *invokeConfig, []grpc.DialOption, []grpc.CallOption) {
+	cfg := &invokeConfig{
 		header: map[string]string{},
 	}
 
 	for _, o := range opts {
-		o(&cfg)
+		o(cfg)
 	}
 
 	if len(cfg.header) > 0 {
 		ctx = metadata.NewOutgoingContext(ctx, metadata.New(cfg.header))
 	}
 
-	return ctx, cfg.dialOpts, cfg.callOpts
+	return ctx, cfg, cfg.dialOpts, cfg.callOpts
 }
 
 // WithHeader sets request header.
@@ -0,0 +1,58 @@
+package grpcmock
+
+import (
+	"fmt"
+	"net"
+
+	"google.golang.org/grpc"
+	channelzService "google.golang.org/grpc/channelz/service"
+	"google.golang.org/grpc/keepalive"
+)
+
+// WithServerKeepaliveParams sets the keepalive.ServerParameters used by the
+// mock server.
+func WithServerKeepaliveParams(p keepalive.ServerParameters) ServerOption {
+	return func(s *Server) {
+		s.grpcOpts = append(s.grpcOpts, grpc.KeepaliveParams(p))
+	}
+}
+
+// WithServerKeepaliveEnforcementPolicy sets the keepalive.EnforcementPolicy
+// used by the mock server to decide whether a client is sending keepalive
+// pings too aggressively.
+func WithServerKeepaliveEnforcementPolicy(p keepalive.EnforcementPolicy) ServerOption {
+	return func(s *Server) {
+		s.grpcOpts = append(s.grpcOpts, grpc.KeepaliveEnforcementPolicy(p))
+	}
+}
+
+// WithKeepaliveParams sets the keepalive.ClientParameters used by the
+// connection, instead of requiring the caller to smuggle them through
+// WithDialOptions.
+func WithKeepaliveParams(p keepalive.ClientParameters) InvokeOption {
+	return WithDialOptions(grpc.WithKeepaliveParams(p))
+}
+
+// WithChannelz exposes channelz.Service on adminListener.
+func WithChannelz(adminListener net.Listener) ServerOption {
+	return func(s *Server) {
+		s.channelzListener = adminListener
+	}
+}
+
+// serveChannelz starts a dedicated gRPC server exposing channelz.Service on
+// l. The caller is responsible for stopping the returned server once it is
+// done with it.
+func serveChannelz(l net.Listener) (*grpc.Server, error) {
+	if l == nil {
+		return nil, fmt.Errorf("channelz admin listener is nil") //nolint: goerr113
+	}
+
+	admin := grpc.NewServer()
+
+	channelzService.RegisterChannelzServiceToServer(admin)
+
+	go func() { _ = admin.Serve(l) }()
+
+	return admin, nil
+}
@@ -0,0 +1,26 @@
+package grpcmock
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func TestWithChannelz_SetsListener(t *testing.T) {
+	t.Parallel()
+
+	l := bufconn.Listen(defaultBufConnSize)
+
+	s := NewServer(WithChannelz(l))
+
+	assert.Same(t, l, s.channelzListener)
+}
+
+func TestServeChannelz_NilListener(t *testing.T) {
+	t.Parallel()
+
+	_, err := serveChannelz(nil)
+
+	assert.EqualError(t, err, "channelz admin listener is nil")
+}
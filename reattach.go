@@ -0,0 +1,242 @@
+package grpcmock
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/test/bufconn"
+
+	"go.nhat.io/grpcmock/reattach"
+)
+
+// ReattachEnvVar is the environment variable that Serve publishes its
+// endpoint to, and that WithReattach reads from.
+const ReattachEnvVar = "GRPCMOCK_REATTACH"
+
+// Serve boots the mock server built from opts, serializes its endpoint as a
+// reattach.Config into the GRPCMOCK_REATTACH environment variable, prints it
+// to stdout, and blocks until ctx is canceled or the process receives
+// SIGTERM/os.Interrupt. If the server is bound to an in-process
+// *bufconn.Listener, it is bridged onto a Unix domain socket for the
+// lifetime of the call, since a bufconn only exists in the process that
+// created it. See WithReattach.
+func Serve(ctx context.Context, opts ...ServerOption) error {
+	srv := NewServer(opts...)
+
+	cfg, cleanup, err := reattachConfigOf(srv)
+	if err != nil {
+		return err
+	}
+
+	defer cleanup()
+
+	data, err := cfg.Marshal()
+	if err != nil {
+		return err
+	}
+
+	if err := os.Setenv(ReattachEnvVar, string(data)); err != nil {
+		return fmt.Errorf("could not set %s: %w", ReattachEnvVar, err)
+	}
+
+	fmt.Println(string(data)) //nolint: forbidigo
+
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	return srv.Serve(ctx)
+}
+
+// reattachConfigOf builds the reattach.Config advertising srv's endpoint,
+// and a cleanup function the caller must invoke once the server is done.
+// When srv is bound to a *bufconn.Listener, it is bridged onto a Unix
+// domain socket whose path whatever reads GRPCMOCK_REATTACH dials, since a
+// bufconn cannot be dialed from another process.
+func reattachConfigOf(srv *Server) (reattach.Config, func(), error) {
+	cfg := reattach.Config{TLSCertFingerprint: tlsCertFingerprint(srv.tlsCert)}
+
+	if l, ok := srv.Listener().(*bufconn.Listener); ok {
+		socket, cleanup, err := bridgeBufConnOverUnixSocket(l)
+		if err != nil {
+			return reattach.Config{}, nil, err
+		}
+
+		cfg.Network = "unix"
+		cfg.Address = socket
+
+		return cfg, cleanup, nil
+	}
+
+	cfg.Network = srv.Listener().Addr().Network()
+	cfg.Address = srv.Listener().Addr().String()
+
+	return cfg, func() {}, nil
+}
+
+// bridgeBufConnOverUnixSocket listens on a fresh Unix domain socket and
+// proxies every accepted connection to a fresh l.Dial(), so that a bufconn
+// which otherwise never leaves its own process can be reattached to by other
+// processes dialing the socket path. It returns the socket path
+// and a cleanup function that closes the listener and removes the socket.
+func bridgeBufConnOverUnixSocket(l *bufconn.Listener) (string, func(), error) {
+	dir, err := os.MkdirTemp("", "grpcmock-reattach-*")
+	if err != nil {
+		return "", nil, fmt.Errorf("could not create reattach socket dir: %w", err)
+	}
+
+	path := filepath.Join(dir, "bufconn.sock")
+
+	bridge, err := net.Listen("unix", path)
+	if err != nil {
+		_ = os.RemoveAll(dir)
+
+		return "", nil, fmt.Errorf("could not listen on %s: %w", path, err)
+	}
+
+	go acceptBridgeConns(bridge, l)
+
+	cleanup := func() {
+		_ = bridge.Close()
+		_ = os.RemoveAll(dir)
+	}
+
+	return path, cleanup, nil
+}
+
+func acceptBridgeConns(bridge net.Listener, l *bufconn.Listener) {
+	for {
+		conn, err := bridge.Accept()
+		if err != nil {
+			return
+		}
+
+		go proxyToBufConn(conn, l)
+	}
+}
+
+// proxyToBufConn splices conn, a connection accepted on the bridging Unix
+// socket, with a fresh connection dialed out of l.
+func proxyToBufConn(conn net.Conn, l *bufconn.Listener) {
+	defer conn.Close()
+
+	upstream, err := l.Dial()
+	if err != nil {
+		return
+	}
+
+	defer upstream.Close()
+
+	done := make(chan struct{}, 2)
+
+	go func() {
+		_, _ = io.Copy(upstream, conn)
+		done <- struct{}{}
+	}()
+
+	go func() {
+		_, _ = io.Copy(conn, upstream)
+		done <- struct{}{}
+	}()
+
+	<-done
+}
+
+// WithTLSCertificate configures the TLS certificate the server's listener
+// presents, and the fingerprint advertised through GRPCMOCK_REATTACH so
+// WithReattach can verify it is dialing the right server.
+func WithTLSCertificate(cert tls.Certificate) ServerOption {
+	return func(s *Server) {
+		s.tlsCert = &cert
+		s.grpcOpts = append(s.grpcOpts, grpc.Creds(credentials.NewServerTLSFromCert(&cert)))
+	}
+}
+
+func tlsCertFingerprint(cert *tls.Certificate) string {
+	if cert == nil || len(cert.Certificate) == 0 {
+		return ""
+	}
+
+	sum := sha256.Sum256(cert.Certificate[0])
+
+	return hex.EncodeToString(sum[:])
+}
+
+// verifyTLSFingerprint builds a tls.Config.VerifyPeerCertificate callback
+// that accepts the handshake only if one of the presented certificates
+// matches the advertised fingerprint.
+func verifyTLSFingerprint(fingerprint string) func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+	return func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+		for _, raw := range rawCerts {
+			sum := sha256.Sum256(raw)
+
+			if hex.EncodeToString(sum[:]) == fingerprint {
+				return nil
+			}
+		}
+
+		return fmt.Errorf("reattach: server certificate does not match the advertised fingerprint %q", fingerprint) //nolint: goerr113
+	}
+}
+
+// WithReattach dials the mock server described by the GRPCMOCK_REATTACH
+// environment variable instead of the method's own host, bypassing
+// parseMethod's URL parsing for the host portion. name currently has no
+// effect beyond documenting intent: only one reattached server is supported
+// per process.
+func WithReattach(name string) InvokeOption {
+	return func(c *invokeConfig) {
+		c.reattachName = name
+		c.useReattach = true
+	}
+}
+
+// reattachDialTarget resolves the dial address, the bare method path, and
+// any extra dial options (e.g. a Unix socket dialer, or TLS fingerprint
+// verification) needed to reach the server advertised in GRPCMOCK_REATTACH.
+func reattachDialTarget(method string, dialOpts []grpc.DialOption) (string, string, []grpc.DialOption, error) {
+	raw := os.Getenv(ReattachEnvVar)
+	if raw == "" {
+		return "", "", nil, fmt.Errorf("%s is not set", ReattachEnvVar) //nolint: goerr113
+	}
+
+	cfg, err := reattach.Unmarshal([]byte(raw))
+	if err != nil {
+		return "", "", nil, err
+	}
+
+	_, method, err = parseMethod(method)
+	if err != nil {
+		return "", "", nil, fmt.Errorf("could not parse method url: %w", err)
+	}
+
+	if cfg.TLSCertFingerprint != "" {
+		dialOpts = append(dialOpts, grpc.WithTransportCredentials(credentials.NewTLS(&tls.Config{
+			InsecureSkipVerify:    true, //nolint:gosec // verified by VerifyPeerCertificate below instead
+			VerifyPeerCertificate: verifyTLSFingerprint(cfg.TLSCertFingerprint),
+		})))
+	}
+
+	if cfg.Network == "unix" {
+		addr := cfg.Address
+
+		dialOpts = append(dialOpts, grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, "unix", addr)
+		}))
+
+		return "passthrough:///" + addr, method, dialOpts, nil
+	}
+
+	return cfg.Address, method, dialOpts, nil
+}
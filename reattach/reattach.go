@@ -0,0 +1,47 @@
+// Package reattach describes how a short-lived test process can find and
+// dial a long-lived, out-of-process mock server started with
+// grpcmock.Serve.
+package reattach
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Config describes where a reattachable mock server can be reached. It is
+// serialized into the GRPCMOCK_REATTACH environment variable by
+// grpcmock.Serve and decoded back by grpcmock.WithReattach.
+type Config struct {
+	// Network is the network the server is listening on, e.g. "tcp" or
+	// "unix". An in-process *bufconn.Listener is bridged onto a "unix"
+	// socket so that it can be dialed from another process; see
+	// grpcmock.Serve.
+	Network string `json:"network"`
+	// Address is the listener address, in the form accepted by Network.
+	Address string `json:"address"`
+	// TLSCertFingerprint is the SHA-256 fingerprint of the server
+	// certificate, set when the listener requires TLS.
+	TLSCertFingerprint string `json:"tls_cert_fingerprint,omitempty"`
+}
+
+// Marshal serializes c as JSON, ready to be stored in the
+// GRPCMOCK_REATTACH environment variable.
+func (c Config) Marshal() ([]byte, error) {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return nil, fmt.Errorf("could not marshal reattach config: %w", err)
+	}
+
+	return data, nil
+}
+
+// Unmarshal decodes a Config previously produced by Config.Marshal.
+func Unmarshal(data []byte) (Config, error) {
+	var c Config
+
+	if err := json.Unmarshal(data, &c); err != nil {
+		return Config{}, fmt.Errorf("could not unmarshal reattach config: %w", err)
+	}
+
+	return c, nil
+}
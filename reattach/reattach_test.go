@@ -0,0 +1,36 @@
+package reattach_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.nhat.io/grpcmock/reattach"
+)
+
+func TestConfig_MarshalUnmarshal(t *testing.T) {
+	t.Parallel()
+
+	cfg := reattach.Config{
+		Network:            "unix",
+		Address:            "/tmp/grpcmock-reattach/bufconn.sock",
+		TLSCertFingerprint: "deadbeef",
+	}
+
+	data, err := cfg.Marshal()
+	require.NoError(t, err)
+
+	got, err := reattach.Unmarshal(data)
+	require.NoError(t, err)
+
+	assert.Equal(t, cfg, got)
+}
+
+func TestUnmarshal_Invalid(t *testing.T) {
+	t.Parallel()
+
+	_, err := reattach.Unmarshal([]byte("not json"))
+
+	assert.Error(t, err)
+}
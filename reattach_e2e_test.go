@@ -0,0 +1,64 @@
+package grpcmock
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	rpb "google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
+)
+
+// TestReattach_BufConnBridge_CompletesRPC boots a Server on its default
+// in-process *bufconn.Listener, bridges it onto a Unix domain socket the
+// way Serve does, and dials it back through WithReattach's
+// reattachDialTarget — exercising bridgeBufConnOverUnixSocket,
+// acceptBridgeConns and proxyToBufConn end to end instead of just their
+// unit parts.
+func TestReattach_BufConnBridge_CompletesRPC(t *testing.T) {
+	t.Parallel()
+
+	srv := NewServer(WithReflection())
+
+	cfg, cleanup, err := reattachConfigOf(srv)
+	require.NoError(t, err)
+
+	t.Cleanup(cleanup)
+
+	data, err := cfg.Marshal()
+	require.NoError(t, err)
+
+	t.Setenv(ReattachEnvVar, string(data))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	go func() { _ = srv.Serve(ctx) }()
+
+	addr, method, dialOpts, err := reattachDialTarget(
+		"/grpc.reflection.v1alpha.ServerReflection/ServerReflectionInfo",
+		[]grpc.DialOption{grpc.WithInsecure()}, //nolint:staticcheck
+	)
+	require.NoError(t, err)
+
+	dialCtx, dialCancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer dialCancel()
+
+	conn, err := grpc.DialContext(dialCtx, addr, dialOpts...)
+	require.NoError(t, err)
+
+	t.Cleanup(func() { _ = conn.Close() })
+
+	client := rpb.NewServerReflectionClient(conn)
+
+	stream, err := client.ServerReflectionInfo(dialCtx)
+	require.NoError(t, err)
+
+	require.NoError(t, stream.Send(&rpb.ServerReflectionRequest{
+		MessageRequest: &rpb.ServerReflectionRequest_ListServices{},
+	}))
+
+	_, err = stream.Recv()
+	require.NoError(t, err, "method %q should have been reachable through the bridged bufconn", method)
+}
@@ -0,0 +1,28 @@
+package grpcmock
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTLSCertFingerprint_NilCert(t *testing.T) {
+	t.Parallel()
+
+	assert.Empty(t, tlsCertFingerprint(nil))
+}
+
+func TestVerifyTLSFingerprint(t *testing.T) {
+	t.Parallel()
+
+	raw := []byte("a fake certificate, for hashing purposes only")
+	sum := sha256.Sum256(raw)
+	fingerprint := hex.EncodeToString(sum[:])
+
+	verify := verifyTLSFingerprint(fingerprint)
+
+	assert.NoError(t, verify([][]byte{raw}, nil))
+	assert.Error(t, verify([][]byte{[]byte("something else")}, nil))
+}
@@ -0,0 +1,203 @@
+package grpcmock
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/stats"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/tap"
+)
+
+// CallEvent is a single recorded RPC, captured from the moment it was
+// accepted by the server to the moment its status was written back to the
+// client.
+type CallEvent struct {
+	Method     string
+	Header     metadata.MD
+	Payload    [][]byte
+	Peer       string
+	StatusCode codes.Code
+	StatusMsg  string
+	StartTime  time.Time
+	Duration   time.Duration
+}
+
+// Recorder is an append-only, in-memory journal of every RPC handled by the
+// mock server.
+type Recorder struct {
+	mu    sync.Mutex
+	calls []CallEvent
+}
+
+// NewRecorder initializes an empty Recorder.
+func NewRecorder() *Recorder {
+	return &Recorder{}
+}
+
+// Calls returns every recorded call for the given fully-qualified method, in
+// the order they completed. The method is in the
+// "package.Service/Method" form, without the leading slash.
+func (r *Recorder) Calls(method string) []CallEvent {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	result := make([]CallEvent, 0, len(r.calls))
+
+	for _, c := range r.calls {
+		if c.Method == method {
+			result = append(result, c)
+		}
+	}
+
+	return result
+}
+
+// All returns every recorded call, regardless of method.
+func (r *Recorder) All() []CallEvent {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	result := make([]CallEvent, len(r.calls))
+	copy(result, r.calls)
+
+	return result
+}
+
+// Reset discards every recorded call.
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.calls = nil
+}
+
+func (r *Recorder) append(c CallEvent) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.calls = append(r.calls, c)
+}
+
+// recorderCallKey is the context key, set in TagRPC, used to carry the
+// in-flight CallEvent between the stats.Handler RPC callbacks.
+type recorderCallKey struct{}
+
+// recordingStatsHandler is a stats.Handler that journals every RPC into a
+// Recorder, then forwards the calls to an optional, user-supplied
+// stats.Handler so it can be chained with things like OpenTelemetry or
+// Prometheus handlers.
+type recordingStatsHandler struct {
+	recorder *Recorder
+	next     stats.Handler
+}
+
+func newRecordingStatsHandler(r *Recorder, next stats.Handler) *recordingStatsHandler {
+	return &recordingStatsHandler{recorder: r, next: next}
+}
+
+func (h *recordingStatsHandler) TagRPC(ctx context.Context, info *stats.RPCTagInfo) context.Context {
+	ctx = context.WithValue(ctx, recorderCallKey{}, &CallEvent{
+		Method:    trimLeadingSlash(info.FullMethodName),
+		StartTime: time.Now(),
+	})
+
+	if h.next != nil {
+		ctx = h.next.TagRPC(ctx, info)
+	}
+
+	return ctx
+}
+
+func (h *recordingStatsHandler) HandleRPC(ctx context.Context, s stats.RPCStats) {
+	if h.next != nil {
+		defer h.next.HandleRPC(ctx, s)
+	}
+
+	event, ok := ctx.Value(recorderCallKey{}).(*CallEvent)
+	if !ok {
+		return
+	}
+
+	switch v := s.(type) {
+	case *stats.InHeader:
+		event.Header = v.Header
+
+		if p, ok := peer.FromContext(ctx); ok {
+			event.Peer = p.Addr.String()
+		}
+
+	case *stats.InPayload:
+		event.Payload = append(event.Payload, clonePayload(v.Data))
+
+	case *stats.OutPayload:
+		event.Payload = append(event.Payload, clonePayload(v.Data))
+
+	case *stats.End:
+		event.Duration = time.Since(event.StartTime)
+
+		st, _ := status.FromError(v.Error)
+		event.StatusCode = st.Code()
+		event.StatusMsg = st.Message()
+
+		h.recorder.append(*event)
+	}
+}
+
+func (h *recordingStatsHandler) TagConn(ctx context.Context, info *stats.ConnTagInfo) context.Context {
+	if h.next != nil {
+		return h.next.TagConn(ctx, info)
+	}
+
+	return ctx
+}
+
+func (h *recordingStatsHandler) HandleConn(ctx context.Context, s stats.ConnStats) {
+	if h.next != nil {
+		h.next.HandleConn(ctx, s)
+	}
+}
+
+func clonePayload(data interface{}) []byte {
+	b, ok := data.([]byte)
+	if !ok {
+		return nil
+	}
+
+	out := make([]byte, len(b))
+	copy(out, b)
+
+	return out
+}
+
+func trimLeadingSlash(s string) string {
+	if len(s) > 0 && s[0] == '/' {
+		return s[1:]
+	}
+
+	return s
+}
+
+// WithStatsHandler registers a stats.Handler on the mock server. It is
+// chained after the internal recording handler that feeds Server.Recorder(),
+// so users can also wire up OpenTelemetry/Prometheus handlers without losing
+// call history.
+func WithStatsHandler(h stats.Handler) ServerOption {
+	return func(s *Server) {
+		s.statsHandler = h
+	}
+}
+
+// WithTap registers a tap.ServerInHandle on the mock server, run before a
+// call reaches the matcher pipeline. It can be used to reject or delay
+// calls, e.g. returning codes.ResourceExhausted to simulate throttling under
+// load.
+func WithTap(h tap.ServerInHandle) ServerOption {
+	return func(s *Server) {
+		s.tap = h
+	}
+}
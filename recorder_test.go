@@ -0,0 +1,135 @@
+package grpcmock
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/stats"
+
+	"go.nhat.io/grpcmock/service"
+)
+
+func TestRecorder_Calls(t *testing.T) {
+	t.Parallel()
+
+	r := NewRecorder()
+
+	r.append(CallEvent{Method: "grpctest.Greeter/SayHello", StatusCode: codes.OK})
+	r.append(CallEvent{Method: "grpctest.Greeter/SayGoodbye", StatusCode: codes.OK})
+	r.append(CallEvent{Method: "grpctest.Greeter/SayHello", StatusCode: codes.NotFound})
+
+	calls := r.Calls("grpctest.Greeter/SayHello")
+
+	assert.Len(t, calls, 2)
+	assert.Equal(t, codes.OK, calls[0].StatusCode)
+	assert.Equal(t, codes.NotFound, calls[1].StatusCode)
+
+	assert.Len(t, r.All(), 3)
+	assert.Empty(t, r.Calls("grpctest.Greeter/Unknown"))
+}
+
+func TestRecorder_Reset(t *testing.T) {
+	t.Parallel()
+
+	r := NewRecorder()
+
+	r.append(CallEvent{Method: "grpctest.Greeter/SayHello", StartTime: time.Now()})
+	assert.Len(t, r.All(), 1)
+
+	r.Reset()
+
+	assert.Empty(t, r.All())
+}
+
+func TestBaseExpectation_RecordedCalls(t *testing.T) {
+	t.Parallel()
+
+	t.Run("no recorder attached", func(t *testing.T) {
+		t.Parallel()
+
+		e := &baseExpectation{}
+
+		assert.Nil(t, e.RecordedCalls())
+	})
+
+	t.Run("recorder attached via Server.addExpectation", func(t *testing.T) {
+		t.Parallel()
+
+		svc := &service.Method{}
+		e := &baseExpectation{locker: &noopLocker{}, serviceDesc: svc}
+
+		srv := NewServer()
+		srv.addExpectation(e)
+		srv.Recorder().append(CallEvent{Method: svc.FullName()})
+
+		assert.Len(t, e.RecordedCalls(), 1)
+	})
+}
+
+func TestRecordingStatsHandler_RecordsInAndOutPayloads(t *testing.T) {
+	t.Parallel()
+
+	r := NewRecorder()
+	h := newRecordingStatsHandler(r, nil)
+
+	ctx := h.TagRPC(context.Background(), &stats.RPCTagInfo{FullMethodName: "/grpctest.Greeter/SayHello"})
+
+	h.HandleRPC(ctx, &stats.InPayload{Data: []byte("request")})
+	h.HandleRPC(ctx, &stats.OutPayload{Data: []byte("response")})
+	h.HandleRPC(ctx, &stats.End{})
+
+	calls := r.Calls("grpctest.Greeter/SayHello")
+	require.Len(t, calls, 1)
+
+	assert.Equal(t, codes.OK, calls[0].StatusCode)
+	assert.Equal(t, [][]byte{[]byte("request"), []byte("response")}, calls[0].Payload)
+}
+
+func TestRecordingStatsHandler_ChainsNextHandler(t *testing.T) {
+	t.Parallel()
+
+	var tagged, handled bool
+
+	next := &stubStatsHandler{
+		onTagRPC:    func() { tagged = true },
+		onHandleRPC: func() { handled = true },
+	}
+
+	h := newRecordingStatsHandler(NewRecorder(), next)
+
+	ctx := h.TagRPC(context.Background(), &stats.RPCTagInfo{FullMethodName: "/grpctest.Greeter/SayHello"})
+	h.HandleRPC(ctx, &stats.End{})
+
+	assert.True(t, tagged)
+	assert.True(t, handled)
+}
+
+type stubStatsHandler struct {
+	onTagRPC    func()
+	onHandleRPC func()
+}
+
+func (h *stubStatsHandler) TagRPC(ctx context.Context, _ *stats.RPCTagInfo) context.Context {
+	h.onTagRPC()
+
+	return ctx
+}
+
+func (h *stubStatsHandler) HandleRPC(context.Context, stats.RPCStats) {
+	h.onHandleRPC()
+}
+
+func (h *stubStatsHandler) TagConn(ctx context.Context, _ *stats.ConnTagInfo) context.Context {
+	return ctx
+}
+
+func (h *stubStatsHandler) HandleConn(context.Context, stats.ConnStats) {}
+
+type noopLocker struct{}
+
+func (noopLocker) Lock()   {}
+func (noopLocker) Unlock() {}
@@ -0,0 +1,370 @@
+package grpcmock
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/reflection"
+	rpb "google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+
+	"go.nhat.io/grpcmock/service"
+)
+
+// WithReflection registers the standard gRPC Server Reflection Protocol
+// (grpc.reflection.v1alpha.ServerReflection) on the mock server, built from
+// the fd set of the services that have been registered on it. Clients that
+// do not have the generated stubs can then discover the mocked services and
+// methods the same way they would against a real server.
+func WithReflection() ServerOption {
+	return func(s *Server) {
+		s.reflection = true
+	}
+}
+
+// registerReflection installs a grpc_reflection_v1alpha.ServerReflectionServer
+// on srv, backed by the file descriptors of the given methods.
+func registerReflection(srv *grpc.Server, methods []service.Method) error {
+	files, err := methodFileDescriptors(methods)
+	if err != nil {
+		return fmt.Errorf("could not build reflection descriptors: %w", err)
+	}
+
+	registerServiceInfo(srv, methods)
+
+	reflectionServer := reflection.NewServer(reflection.ServerOptions{
+		Services:           srv,
+		DescriptorResolver: files,
+		ExtensionResolver:  files,
+	})
+
+	rpb.RegisterServerReflectionServer(srv, reflectionServer)
+
+	return nil
+}
+
+// registerServiceInfo registers a bare *grpc.ServiceDesc per distinct
+// service named in methods, so srv.GetServiceInfo() — what ListServices
+// reads off of srv — actually lists the mocked services. These
+// descriptors carry no method handlers of their own; they only make the
+// services visible to reflection.
+func registerServiceInfo(srv *grpc.Server, methods []service.Method) {
+	registered := make(map[string]struct{})
+
+	for _, m := range methods {
+		svc, _, err := splitFullMethod(m.FullName())
+		if err != nil {
+			continue
+		}
+
+		if _, ok := registered[svc]; ok {
+			continue
+		}
+
+		registered[svc] = struct{}{}
+
+		srv.RegisterService(&grpc.ServiceDesc{ServiceName: svc, HandlerType: (*interface{})(nil)}, nil)
+	}
+}
+
+// methodFileDescriptors builds a protodesc.Resolver out of the file
+// descriptors referenced by the given methods, so reflection can describe
+// them without the caller linking in the generated *.pb.go packages.
+func methodFileDescriptors(methods []service.Method) (*protoregistryFiles, error) {
+	result := newProtoregistryFiles()
+
+	for _, m := range methods {
+		fd, err := m.FileDescriptor()
+		if err != nil {
+			return nil, fmt.Errorf("method %q: %w", m.FullName(), err)
+		}
+
+		if err := result.add(fd); err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+// protoregistryFiles is a small, append-only set of file descriptors used to
+// answer reflection lookups for methods registered on the mock server.
+type protoregistryFiles struct {
+	files *protoregistry.Files
+}
+
+func newProtoregistryFiles() *protoregistryFiles {
+	return &protoregistryFiles{files: new(protoregistry.Files)}
+}
+
+func (f *protoregistryFiles) add(fd protoreflect.FileDescriptor) error {
+	if _, err := f.files.FindFileByPath(fd.Path()); err == nil {
+		return nil
+	}
+
+	return f.files.RegisterFile(fd)
+}
+
+func (f *protoregistryFiles) FindFileByPath(path string) (protoreflect.FileDescriptor, error) {
+	return f.files.FindFileByPath(path)
+}
+
+func (f *protoregistryFiles) FindDescriptorByName(name protoreflect.FullName) (protoreflect.Descriptor, error) {
+	return f.files.FindDescriptorByName(name)
+}
+
+func (f *protoregistryFiles) FindExtensionByName(field protoreflect.FullName) (protoreflect.ExtensionType, error) {
+	return protoregistry.GlobalTypes.FindExtensionByName(field)
+}
+
+func (f *protoregistryFiles) FindExtensionByNumber(message protoreflect.FullName, field protoreflect.FieldNumber) (protoreflect.ExtensionType, error) {
+	return protoregistry.GlobalTypes.FindExtensionByNumber(message, field)
+}
+
+var _ protodesc.Resolver = (*protoregistryFiles)(nil)
+
+// WithServerReflection resolves the method's wire types via the gRPC Server
+// Reflection Protocol instead of requiring the caller to pass concrete
+// request/response structs. It lets InvokeUnary and InvokeServerStream be
+// driven purely from JSON payloads against a schema that is only known at
+// run time, which is handy for CLI fuzzers and ad-hoc test harnesses. For
+// InvokeServerStream only the request is built from the reflected schema;
+// the response messages are decoded by the handler into the types it passes
+// to RecvMsg.
+//
+// See:
+// 	- grpcmock.WithReflection()
+func WithServerReflection() InvokeOption {
+	return func(c *invokeConfig) {
+		c.useServerReflection = true
+	}
+}
+
+// resolveViaReflection asks the ServerReflection service reachable over conn for the
+// descriptor of method, and returns a dynamicpb message factory for both the
+// input and the output types.
+func resolveViaReflection(ctx context.Context, conn *grpc.ClientConn, method string) (*reflectionMethod, error) {
+	client := rpb.NewServerReflectionClient(conn)
+
+	stream, err := client.ServerReflectionInfo(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("could not open reflection stream: %w", err)
+	}
+
+	defer func() { _ = stream.CloseSend() }()
+
+	return newReflectionMethod(stream, method)
+}
+
+// reflectionMethod knows how to build empty input/output messages for a
+// method discovered via server reflection.
+type reflectionMethod struct {
+	input  protoreflect.MessageType
+	output protoreflect.MessageType
+}
+
+// newInput returns a new, empty instance of the method's request message.
+func (m *reflectionMethod) newInput() *dynamicpb.Message {
+	return dynamicpb.NewMessage(m.input.Descriptor())
+}
+
+// newOutput returns a new, empty instance of the method's response message.
+func (m *reflectionMethod) newOutput() *dynamicpb.Message {
+	return dynamicpb.NewMessage(m.output.Descriptor())
+}
+
+// newReflectionMethod asks the ServerReflectionInfo stream for the file
+// descriptor containing fullMethod (in the "/package.Service/Method" form)
+// and resolves its input and output message types out of it.
+func newReflectionMethod(stream rpb.ServerReflection_ServerReflectionInfoClient, fullMethod string) (*reflectionMethod, error) {
+	svc, methodName, err := splitFullMethod(fullMethod)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := stream.Send(&rpb.ServerReflectionRequest{
+		MessageRequest: &rpb.ServerReflectionRequest_FileContainingSymbol{
+			FileContainingSymbol: svc,
+		},
+	}); err != nil {
+		return nil, fmt.Errorf("could not request descriptor for %q: %w", svc, err)
+	}
+
+	resp, err := stream.Recv()
+	if err != nil {
+		return nil, fmt.Errorf("could not receive descriptor for %q: %w", svc, err)
+	}
+
+	fdResp, ok := resp.MessageResponse.(*rpb.ServerReflectionResponse_FileDescriptorResponse)
+	if !ok {
+		return nil, fmt.Errorf("unexpected reflection response for %q: %T", svc, resp.MessageResponse) // nolint: goerr113
+	}
+
+	files := newProtoregistryFiles()
+
+	for _, raw := range fdResp.FileDescriptorResponse.FileDescriptorProto {
+		fdProto := &descriptorpb.FileDescriptorProto{}
+
+		if err := proto.Unmarshal(raw, fdProto); err != nil {
+			return nil, fmt.Errorf("could not parse file descriptor for %q: %w", svc, err)
+		}
+
+		fd, err := protodesc.NewFile(fdProto, files)
+		if err != nil {
+			return nil, fmt.Errorf("could not build file descriptor for %q: %w", svc, err)
+		}
+
+		if err := files.add(fd); err != nil {
+			return nil, err
+		}
+	}
+
+	desc, err := files.FindDescriptorByName(protoreflect.FullName(svc))
+	if err != nil {
+		return nil, fmt.Errorf("could not find service %q: %w", svc, err)
+	}
+
+	svcDesc, ok := desc.(protoreflect.ServiceDescriptor)
+	if !ok {
+		return nil, fmt.Errorf("%q is not a service", svc) // nolint: goerr113
+	}
+
+	methodDesc := svcDesc.Methods().ByName(protoreflect.Name(methodName))
+	if methodDesc == nil {
+		return nil, fmt.Errorf("method %q not found on service %q", methodName, svc) // nolint: goerr113
+	}
+
+	return &reflectionMethod{
+		input:  dynamicpb.NewMessageType(methodDesc.Input()),
+		output: dynamicpb.NewMessageType(methodDesc.Output()),
+	}, nil
+}
+
+// invokeUnaryViaReflection resolves method's wire types over the server
+// reflection service, unmarshals in (a JSON payload) into a dynamic request
+// message, invokes the call and marshals the dynamic response back into out
+// as JSON.
+func invokeUnaryViaReflection(ctx context.Context, conn *grpc.ClientConn, method string, in, out interface{}, callOpts ...grpc.CallOption) error {
+	m, err := resolveViaReflection(ctx, conn, method)
+	if err != nil {
+		return err
+	}
+
+	req, err := m.newInputFromJSON(in)
+	if err != nil {
+		return err
+	}
+
+	resp := m.newOutput()
+
+	if err := conn.Invoke(ctx, method, req, resp, callOpts...); err != nil {
+		return err
+	}
+
+	return marshalJSONTo(resp, out)
+}
+
+// invokeServerStreamViaReflection is the server-streaming counterpart of
+// invokeUnaryViaReflection: the request is built out of the reflected schema,
+// but handle is invoked with the plain grpc.ClientStream, whose RecvMsg
+// decodes into whatever message handle passes to it; responses are not
+// converted to dynamic messages or JSON.
+func invokeServerStreamViaReflection(ctx context.Context, conn *grpc.ClientConn, method string, in interface{}, handle func(stream grpc.ClientStream) error, callOpts ...grpc.CallOption) error {
+	m, err := resolveViaReflection(ctx, conn, method)
+	if err != nil {
+		return err
+	}
+
+	req, err := m.newInputFromJSON(in)
+	if err != nil {
+		return err
+	}
+
+	stream, err := conn.NewStream(ctx, &grpc.StreamDesc{ServerStreams: true}, method, callOpts...)
+	if err != nil {
+		return err
+	}
+
+	if err := stream.SendMsg(req); err != nil {
+		return err
+	}
+
+	if err := stream.CloseSend(); err != nil {
+		return err
+	}
+
+	if handle == nil {
+		return nil
+	}
+
+	return handle(stream)
+}
+
+// newInputFromJSON builds an input message for the method and, if in holds
+// a JSON payload ([]byte or string), unmarshals it into that message.
+func (m *reflectionMethod) newInputFromJSON(in interface{}) (*dynamicpb.Message, error) {
+	req := m.newInput()
+
+	switch payload := in.(type) {
+	case nil:
+		return req, nil
+
+	case []byte:
+		return req, protojson.Unmarshal(payload, req)
+
+	case string:
+		return req, protojson.Unmarshal([]byte(payload), req)
+
+	default:
+		return nil, fmt.Errorf("unsupported payload type for server reflection invoke: %T", in) // nolint: goerr113
+	}
+}
+
+// marshalJSONTo marshals the dynamic response msg as JSON into out, which
+// must be a *[]byte or *string.
+func marshalJSONTo(msg *dynamicpb.Message, out interface{}) error {
+	if out == nil {
+		return nil
+	}
+
+	data, err := protojson.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("could not marshal response: %w", err)
+	}
+
+	switch o := out.(type) {
+	case *[]byte:
+		*o = data
+
+		return nil
+
+	case *string:
+		*o = string(data)
+
+		return nil
+
+	default:
+		return fmt.Errorf("unsupported output type for server reflection invoke: %T", out) // nolint: goerr113
+	}
+}
+
+// splitFullMethod splits a "/package.Service/Method" method name into its
+// fully-qualified service name and bare method name.
+func splitFullMethod(fullMethod string) (string, string, error) {
+	method := strings.TrimPrefix(fullMethod, "/")
+
+	idx := strings.LastIndex(method, "/")
+	if idx < 0 {
+		return "", "", fmt.Errorf("%q is not a valid method name", fullMethod) // nolint: goerr113
+	}
+
+	return method[:idx], method[idx+1:], nil
+}
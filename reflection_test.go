@@ -0,0 +1,57 @@
+package grpcmock
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitFullMethod(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		scenario   string
+		fullMethod string
+		service    string
+		method     string
+		error      string
+	}{
+		{
+			scenario:   "with leading slash",
+			fullMethod: "/grpctest.Greeter/SayHello",
+			service:    "grpctest.Greeter",
+			method:     "SayHello",
+		},
+		{
+			scenario:   "without leading slash",
+			fullMethod: "grpctest.Greeter/SayHello",
+			service:    "grpctest.Greeter",
+			method:     "SayHello",
+		},
+		{
+			scenario:   "missing method",
+			fullMethod: "grpctest.Greeter",
+			error:      `"grpctest.Greeter" is not a valid method name`,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			svc, method, err := splitFullMethod(tc.fullMethod)
+
+			if tc.error != "" {
+				assert.EqualError(t, err, tc.error)
+
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.service, svc)
+			assert.Equal(t, tc.method, method)
+		})
+	}
+}
@@ -0,0 +1,161 @@
+package grpcmock
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// RetryPolicy configures the exponential-backoff retry loop installed by
+// WithRetry. It mirrors gRPC's own connection-backoff configuration (see
+// google.golang.org/grpc/backoff.DefaultConfig).
+type RetryPolicy struct {
+	// BaseDelay is the delay before the first retry. Defaults to 1s.
+	BaseDelay time.Duration
+	// Multiplier is applied to the delay after every attempt. Defaults to 1.6.
+	Multiplier float64
+	// Jitter randomizes the delay by +/- Jitter. Nil defaults to 0.2; pass
+	// a pointer to 0 to disable jitter entirely.
+	Jitter *float64
+	// MaxDelay caps the computed delay. Defaults to 120s.
+	MaxDelay time.Duration
+	// MaxAttempts is the maximum number of attempts, including the first
+	// one. Defaults to 5.
+	MaxAttempts int
+	// Retryable decides whether err should trigger another attempt.
+	// Defaults to retrying on codes.Unavailable, codes.DeadlineExceeded and
+	// codes.ResourceExhausted.
+	Retryable func(err error) bool
+}
+
+// DefaultRetryPolicy returns the RetryPolicy used by WithRetry when called
+// with a zero-value RetryPolicy.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		BaseDelay:   time.Second,
+		Multiplier:  1.6,
+		Jitter:      float64Ptr(0.2),
+		MaxDelay:    120 * time.Second,
+		MaxAttempts: 5,
+		Retryable:   defaultRetryable,
+	}
+}
+
+func float64Ptr(v float64) *float64 {
+	return &v
+}
+
+func defaultRetryable(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.ResourceExhausted:
+		return true
+	default:
+		return false
+	}
+}
+
+func (p RetryPolicy) withDefaults() RetryPolicy {
+	d := DefaultRetryPolicy()
+
+	if p.BaseDelay <= 0 {
+		p.BaseDelay = d.BaseDelay
+	}
+
+	if p.Multiplier <= 0 {
+		p.Multiplier = d.Multiplier
+	}
+
+	if p.Jitter == nil {
+		p.Jitter = d.Jitter
+	}
+
+	if p.MaxDelay <= 0 {
+		p.MaxDelay = d.MaxDelay
+	}
+
+	if p.MaxAttempts <= 0 {
+		p.MaxAttempts = d.MaxAttempts
+	}
+
+	if p.Retryable == nil {
+		p.Retryable = d.Retryable
+	}
+
+	return p
+}
+
+// backoff returns the delay to wait after attempt n (1-based; n is the
+// attempt that just failed) and before attempt n+1, following
+// min(MaxDelay, BaseDelay*Multiplier^(n-1)) * (1 + Jitter*(2*rand()-1)).
+func (p RetryPolicy) backoff(n int) time.Duration {
+	delay := float64(p.BaseDelay) * math.Pow(p.Multiplier, float64(n-1))
+
+	if max := float64(p.MaxDelay); delay > max {
+		delay = max
+	}
+
+	if p.Jitter != nil && *p.Jitter > 0 {
+		delay *= 1 + *p.Jitter*(2*rand.Float64()-1) //nolint:gosec
+	}
+
+	if delay < 0 {
+		delay = 0
+	}
+
+	return time.Duration(delay)
+}
+
+// WithRetry wraps the call in an exponential-backoff retry loop. The
+// original message is re-sent on every attempt; for server-streaming calls,
+// each attempt opens a fresh grpc.ClientStream. Once retries are exhausted,
+// the terminal status.Status is returned unchanged.
+func WithRetry(policy RetryPolicy) InvokeOption {
+	policy = policy.withDefaults()
+
+	return func(c *invokeConfig) {
+		c.retry = &policy
+	}
+}
+
+// retryLoop runs do, retrying it according to policy while ctx still has
+// time left and policy.Retryable allows it.
+func retryLoop(ctx context.Context, policy *RetryPolicy, do func(ctx context.Context) error) error {
+	var err error
+
+	for attempt := 1; attempt <= policy.MaxAttempts; attempt++ {
+		err = do(ctx)
+
+		if err == nil || !policy.Retryable(err) || attempt == policy.MaxAttempts {
+			return err
+		}
+
+		if waitErr := sleepForRetry(ctx, policy.backoff(attempt)); waitErr != nil {
+			return waitErr
+		}
+	}
+
+	return err
+}
+
+func sleepForRetry(ctx context.Context, delay time.Duration) error {
+	if deadline, ok := ctx.Deadline(); ok {
+		if remaining := time.Until(deadline); remaining < delay {
+			delay = remaining
+		}
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,63 @@
+package grpcmock
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetryPolicy_withDefaults(t *testing.T) {
+	t.Parallel()
+
+	p := RetryPolicy{}.withDefaults()
+	d := DefaultRetryPolicy()
+
+	assert.Equal(t, d.BaseDelay, p.BaseDelay)
+	assert.Equal(t, d.Multiplier, p.Multiplier)
+	assert.Equal(t, *d.Jitter, *p.Jitter)
+	assert.Equal(t, d.MaxDelay, p.MaxDelay)
+	assert.Equal(t, d.MaxAttempts, p.MaxAttempts)
+	assert.NotNil(t, p.Retryable)
+}
+
+func TestRetryPolicy_withDefaults_KeepsExplicitValues(t *testing.T) {
+	t.Parallel()
+
+	p := RetryPolicy{
+		BaseDelay:   500 * time.Millisecond,
+		Multiplier:  2,
+		Jitter:      float64Ptr(0.5),
+		MaxDelay:    time.Second,
+		MaxAttempts: 3,
+	}.withDefaults()
+
+	assert.Equal(t, 500*time.Millisecond, p.BaseDelay)
+	assert.InDelta(t, 2, p.Multiplier, 0)
+	assert.InDelta(t, 0.5, *p.Jitter, 0)
+	assert.Equal(t, time.Second, p.MaxDelay)
+	assert.Equal(t, 3, p.MaxAttempts)
+}
+
+func TestRetryPolicy_withDefaults_ExplicitZeroJitterIsNotOverridden(t *testing.T) {
+	t.Parallel()
+
+	p := RetryPolicy{Jitter: float64Ptr(0)}.withDefaults()
+
+	assert.InDelta(t, 0, *p.Jitter, 0)
+}
+
+func TestRetryPolicy_backoff(t *testing.T) {
+	t.Parallel()
+
+	p := RetryPolicy{
+		BaseDelay:  time.Second,
+		Multiplier: 2,
+		Jitter:     float64Ptr(0),
+		MaxDelay:   3 * time.Second,
+	}
+
+	assert.Equal(t, time.Second, p.backoff(1))
+	assert.Equal(t, 2*time.Second, p.backoff(2))
+	assert.Equal(t, 3*time.Second, p.backoff(3)) // clamped by MaxDelay
+}
@@ -0,0 +1,141 @@
+package grpcmock
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/stats"
+	"google.golang.org/grpc/tap"
+	"google.golang.org/grpc/test/bufconn"
+
+	"go.nhat.io/grpcmock/service"
+)
+
+// defaultBufConnSize is the buffer size used for the in-process listener a
+// Server falls back to when no listener is configured via a ServerOption.
+const defaultBufConnSize = 1024 * 1024
+
+// Server is a mock gRPC server. It owns a *grpc.Server, the listener it is
+// bound to, and the bookkeeping that the ServerOption functions in this
+// package configure.
+type Server struct {
+	listener net.Listener
+	grpcOpts []grpc.ServerOption
+
+	services []service.Method
+
+	reflection bool
+
+	statsHandler stats.Handler
+	tap          tap.ServerInHandle
+	recorder     *Recorder
+
+	tlsCert *tls.Certificate
+
+	channelzListener net.Listener
+
+	expectations []*baseExpectation
+
+	server *grpc.Server
+}
+
+// ServerOption configures a Server built by NewServer.
+type ServerOption func(s *Server)
+
+// NewServer builds a Server out of opts. The server does not start
+// listening until Serve is called.
+func NewServer(opts ...ServerOption) *Server {
+	s := &Server{
+		recorder: NewRecorder(),
+	}
+
+	for _, o := range opts {
+		o(s)
+	}
+
+	return s
+}
+
+// Recorder returns the journal of every RPC handled by the server. See
+// WithStatsHandler and WithTap for how it is fed.
+func (s *Server) Recorder() *Recorder {
+	return s.recorder
+}
+
+// addExpectation wires e into the server: e.RecordedCalls() starts
+// reflecting the server's shared Recorder, and e.ServiceMethod() is added to
+// the set of methods discoverable through WithReflection.
+func (s *Server) addExpectation(e *baseExpectation) {
+	e.lock()
+	e.recorder = s.recorder
+	e.unlock()
+
+	s.expectations = append(s.expectations, e)
+	s.services = append(s.services, e.ServiceMethod())
+}
+
+// WithListener sets the net.Listener the server accepts connections on. If
+// it is never called, Listener lazily creates an in-process
+// *bufconn.Listener on first use.
+func WithListener(l net.Listener) ServerOption {
+	return func(s *Server) {
+		s.listener = l
+	}
+}
+
+// Listener returns the net.Listener the server is (or will be) bound to.
+func (s *Server) Listener() net.Listener {
+	if s.listener == nil {
+		s.listener = bufconn.Listen(defaultBufConnSize)
+	}
+
+	return s.listener
+}
+
+// Serve builds the underlying *grpc.Server — installing the recording stats
+// handler, the optional tap, the reflection service when WithReflection was
+// used, and the channelz admin server when WithChannelz was used — then
+// blocks serving requests until ctx is canceled.
+func (s *Server) Serve(ctx context.Context) error {
+	opts := append([]grpc.ServerOption(nil), s.grpcOpts...)
+	opts = append(opts, grpc.StatsHandler(newRecordingStatsHandler(s.recorder, s.statsHandler)))
+
+	if s.tap != nil {
+		opts = append(opts, grpc.InTapHandle(s.tap))
+	}
+
+	s.server = grpc.NewServer(opts...)
+
+	if s.reflection {
+		if err := registerReflection(s.server, s.services); err != nil {
+			return err
+		}
+	}
+
+	if s.channelzListener != nil {
+		channelzServer, err := serveChannelz(s.channelzListener)
+		if err != nil {
+			return err
+		}
+
+		defer channelzServer.GracefulStop()
+	}
+
+	errCh := make(chan error, 1)
+
+	go func() {
+		errCh <- s.server.Serve(s.Listener())
+	}()
+
+	select {
+	case <-ctx.Done():
+		s.server.GracefulStop()
+
+		return ctx.Err()
+
+	case err := <-errCh:
+		return err
+	}
+}
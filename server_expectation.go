@@ -19,6 +19,12 @@ type baseExpectation struct {
 
 	serviceDesc *service.Method
 
+	// recorder, if set, is the journal of every call handled by the parent
+	// Server. It is shared with that Server so that
+	// Server.Recorder().Calls() and RecordedCalls() agree on the same
+	// history.
+	recorder *Recorder
+
 	// requestHeader is a list of expected headers of the given request.
 	requestHeader xmatcher.HeaderMatcher
 	// requestPayload is the expected parameters of the given request.
@@ -60,6 +66,17 @@ func (e *baseExpectation) ServiceMethod() service.Method {
 	return *e.serviceDesc
 }
 
+// RecordedCalls returns every call recorded so far for this expectation's
+// method, whether or not it matched this expectation's own matchers, or nil
+// if no Recorder has been attached to the server.
+func (e *baseExpectation) RecordedCalls() []CallEvent {
+	if e.recorder == nil {
+		return nil
+	}
+
+	return e.recorder.Calls(e.serviceDesc.FullName())
+}
+
 func (e *baseExpectation) HeaderMatcher() xmatcher.HeaderMatcher {
 	e.lock()
 	defer e.unlock()